@@ -22,6 +22,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/VirusTotal/vt-cli/formatter"
 	"github.com/VirusTotal/vt-cli/utils"
 	"github.com/VirusTotal/vt-cli/yaml"
 	"github.com/VirusTotal/vt-go/vt"
@@ -112,6 +114,25 @@ func addYAMLFlag(flags *pflag.FlagSet) {
 		"output in YAML format")
 }
 
+func addFormatFlag(flags *pflag.FlagSet) {
+	flags.StringP(
+		"format", "F", "",
+		`output using a Go text/template, e.g. "{{.id}}" or `+
+			`"table {{.id}}\t{{.attributes.type_description}}"`)
+}
+
+func addJSONFlag(flags *pflag.FlagSet) {
+	flags.Bool(
+		"json", false,
+		"output in JSON format")
+}
+
+func addNDJSONFlag(flags *pflag.FlagSet) {
+	flags.Bool(
+		"ndjson", false,
+		"output newline-delimited JSON, one object per line, as results arrive")
+}
+
 // ReadFile reads the specified file and returns its content. If filename is "-"
 // the data is read from stdin.
 func ReadFile(filename string) ([]byte, error) {
@@ -144,11 +165,25 @@ func PrintCommandLineWithCursor(cmd *cobra.Command, it *vt.Iterator) {
 }
 
 // NewAPIClient returns a new utils.APIClient with the API key specified via
-// command-line argument or config file.
+// command-line argument or config file. When none is configured and stdin
+// is attached to a terminal, the user is prompted for an API key (validated
+// the same way "vt init" does) and the key is saved to the configuration
+// file, instead of failing outright. The other "vt init" defaults are not
+// prompted for here.
 func NewAPIClient() (*utils.APIClient, error) {
 	apikey := viper.GetString("apikey")
 	if apikey == "" {
-		return nil, errors.New("An API key is needed. Either use the --apikey flag or run \"vt init\" to set up your API key")
+		if !isInteractive() {
+			return nil, errors.New("An API key is needed. Either use the --apikey flag or run \"vt init\" to set up your API key")
+		}
+		var err error
+		if apikey, err = promptAndValidateAPIKey(""); err != nil {
+			return nil, err
+		}
+		viper.Set("apikey", apikey)
+		if err := viper.WriteConfig(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not save API key to the configuration file: %v\n", err)
+		}
 	}
 	return utils.NewAPIClient(apikey, fmt.Sprintf("vt-cli %s", Version))
 }
@@ -197,23 +232,39 @@ func (p *ObjectPrinter) Print(objType string, args []string, argRe *regexp.Regex
 
 	go p.client.RetrieveObjects(objType, filteredArgs, objectsCh, errorsCh)
 
-	objs := make([]*vt.Object, 0)
+	sink, err := p.newObjectSink()
+	if err != nil {
+		return err
+	}
+
+	pw := newProgressWriter()
+	defer pw.Close()
 
+	written := false
+	fetched, errs := 0, 0
 	for obj := range objectsCh {
 		if viper.GetBool("identifiers-only") {
 			fmt.Printf("%s\n", obj.ID)
 		} else {
-			objs = append(objs, obj)
+			p.filterAttributes(obj)
+			if err := sink.Write(obj); err != nil {
+				return err
+			}
+			written = true
 		}
+		fetched++
+		pw.Event(progressEvent{ID: obj.ID, Kind: progressDone, Fetched: fetched, Errors: errs, Time: time.Now()})
 	}
 
-	if len(objs) > 0 {
-		if err := p.PrintObjects(objs); err != nil {
+	if written {
+		if err := sink.Flush(); err != nil {
 			return err
 		}
 	}
 
 	for err := range errorsCh {
+		errs++
+		pw.Event(progressEvent{Kind: progressError, Err: err.Error(), Errors: errs, Time: time.Now()})
 		fmt.Fprintln(os.Stderr, err)
 	}
 
@@ -235,22 +286,38 @@ func (p *ObjectPrinter) PrintCollection(collection *url.URL) error {
 
 func (p *ObjectPrinter) PrintIter(it *vt.Iterator) error {
 
-	objs := make([]*vt.Object, 0)
+	sink, err := p.newObjectSink()
+	if err != nil {
+		return err
+	}
+
+	pw := newProgressWriter()
+	defer pw.Close()
+
+	written := false
+	fetched := 0
 	for it.Next() {
 		obj := it.Get()
 		if viper.GetBool("identifiers-only") {
 			fmt.Printf("%s\n", obj.ID)
 		} else {
-			objs = append(objs, obj)
+			p.filterAttributes(obj)
+			if err := sink.Write(obj); err != nil {
+				return err
+			}
+			written = true
 		}
+		fetched++
+		pw.Event(progressEvent{ID: obj.ID, Kind: progressDone, Fetched: fetched, Time: time.Now()})
 	}
 
 	if err := it.Error(); err != nil {
+		pw.Event(progressEvent{Kind: progressError, Err: err.Error(), Errors: 1, Time: time.Now()})
 		return err
 	}
 
-	if len(objs) > 0 {
-		if err := p.PrintObjects(objs); err != nil {
+	if written {
+		if err := sink.Flush(); err != nil {
 			return err
 		}
 	}
@@ -265,34 +332,47 @@ func (p *ObjectPrinter) PrintObject(obj *vt.Object) error {
 	return p.PrintObjects(objs)
 }
 
+// PrintObjects renders objs in full (as opposed to the streaming path used
+// by Print/PrintIter), honoring --include/--exclude, --format/--json/
+// --ndjson/--yaml the same way.
 func (p *ObjectPrinter) PrintObjects(objs []*vt.Object) error {
 
-	list := make([]map[string]interface{}, 0)
+	sink, err := p.newObjectSink()
+	if err != nil {
+		return err
+	}
 
 	for _, obj := range objs {
-		m := obj.Attributes
-		if viper.IsSet("include") && viper.IsSet("exclude") {
-			m = utils.FilterMap(
-				m, viper.GetStringSlice("include"), viper.GetStringSlice("exclude"))
-		}
-		for name, r := range obj.Relationships {
-			if r.IsOneToOne && len(r.RelatedObjects) > 0 {
-				m[name] = r.RelatedObjects[0].ID
-			} else {
-				l := make([]string, 0)
-				for _, obj := range r.RelatedObjects {
-					l = append(l, obj.ID)
-				}
-				m[name] = l
-			}
+		p.filterAttributes(obj)
+		if err := sink.Write(obj); err != nil {
+			return err
 		}
-		key := fmt.Sprintf("%s <%s>", obj.Type, obj.ID)
-		list = append(list, map[string]interface{}{key: m})
 	}
 
-	if err := yaml.NewColorEncoder(p.w, colorScheme).Encode(list); err != nil {
-		return err
+	return sink.Flush()
+}
+
+// filterAttributes narrows obj.Attributes down to the fields requested via
+// --include/--exclude, when both flags are set.
+func (p *ObjectPrinter) filterAttributes(obj *vt.Object) {
+	if viper.IsSet("include") && viper.IsSet("exclude") {
+		obj.Attributes = utils.FilterMap(
+			obj.Attributes, viper.GetStringSlice("include"), viper.GetStringSlice("exclude"))
 	}
+}
 
-	return p.w.Flush()
+// formatTemplate returns the --format template that should be used for
+// rendering, falling back to the default registered for the current
+// command. An empty string means --yaml (the original behavior) applies.
+func (p *ObjectPrinter) formatTemplate() string {
+	if tmpl := viper.GetString("format"); tmpl != "" {
+		return tmpl
+	}
+	if viper.GetBool("yaml") {
+		return ""
+	}
+	if tmpl, ok := formatter.Default(p.cmd.CommandPath()); ok {
+		return tmpl
+	}
+	return ""
 }
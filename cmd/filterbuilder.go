@@ -0,0 +1,109 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/pflag"
+)
+
+func addInteractiveFlag(flags *pflag.FlagSet) {
+	flags.Bool(
+		"interactive", false,
+		"build the search query interactively; the result is appended to any query given on the command line")
+}
+
+// filterModifier is one of the VT Intelligence search modifiers offered by
+// the interactive filter builder.
+type filterModifier struct {
+	name   string
+	help   string
+	prompt func() (string, error)
+}
+
+var filterModifiers = []filterModifier{
+	{"type", "file/URL/domain/IP type, e.g. peexe, apk, docx", promptFreeText("Type (leave blank to skip):")},
+	{"size", "file size, e.g. 10MB-, -1MB, 100KB+", promptFreeText("Size (leave blank to skip):")},
+	{"p", "positive AV detections, e.g. 5+", promptFreeText("Positives (leave blank to skip):")},
+	{"fs", "first submission date, e.g. 2020-01-01+", promptFreeText("First submission date (leave blank to skip):")},
+	{"ls", "last submission date, e.g. 2020-01-01-", promptFreeText("Last submission date (leave blank to skip):")},
+}
+
+func promptFreeText(message string) func() (string, error) {
+	return func() (string, error) {
+		answer := ""
+		if err := survey.AskOne(&survey.Input{Message: message}, &answer); err != nil {
+			return "", err
+		}
+		return answer, nil
+	}
+}
+
+// BuildFilterInteractively walks the user through the VT Intelligence
+// search modifiers via a multiselect prompt, followed by one text prompt
+// per chosen modifier, and assembles a
+// --filter string out of the ones they choose to set, e.g.
+// "type:peexe size:10MB- p:5+". It's used by "vt search --interactive".
+func BuildFilterInteractively() (string, error) {
+	chosen := make([]filterTerm, 0)
+	options := make([]string, len(filterModifiers))
+	for i, m := range filterModifiers {
+		options[i] = fmt.Sprintf("%s - %s", m.name, m.help)
+	}
+
+	selected := make([]int, 0)
+	if err := survey.AskOne(
+		&survey.MultiSelect{
+			Message: "Which modifiers do you want to add to the filter?",
+			Options: options,
+		},
+		&selected,
+	); err != nil {
+		return "", err
+	}
+
+	for _, i := range selected {
+		m := filterModifiers[i]
+		value, err := m.prompt()
+		if err != nil {
+			return "", err
+		}
+		chosen = append(chosen, filterTerm{name: m.name, value: value})
+	}
+
+	return assembleFilter(chosen), nil
+}
+
+// filterTerm pairs a modifier name, e.g. "type", with the value the user
+// entered for it.
+type filterTerm struct {
+	name  string
+	value string
+}
+
+// assembleFilter joins terms into a --filter string, e.g.
+// "type:peexe size:10MB-", skipping any term the user left blank.
+func assembleFilter(terms []filterTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t.value == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%s", t.name, t.value))
+	}
+	return strings.Join(parts, " ")
+}
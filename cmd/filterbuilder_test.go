@@ -0,0 +1,64 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestAssembleFilter(t *testing.T) {
+	cases := []struct {
+		name  string
+		terms []filterTerm
+		want  string
+	}{
+		{
+			name: "all terms set",
+			terms: []filterTerm{
+				{name: "type", value: "peexe"},
+				{name: "size", value: "10MB-"},
+				{name: "p", value: "5+"},
+			},
+			want: "type:peexe size:10MB- p:5+",
+		},
+		{
+			name: "blank terms are skipped",
+			terms: []filterTerm{
+				{name: "type", value: "peexe"},
+				{name: "size", value: ""},
+				{name: "p", value: "5+"},
+			},
+			want: "type:peexe p:5+",
+		},
+		{
+			name:  "no terms",
+			terms: []filterTerm{},
+			want:  "",
+		},
+		{
+			name: "every term blank",
+			terms: []filterTerm{
+				{name: "type", value: ""},
+				{name: "size", value: ""},
+			},
+			want: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := assembleFilter(c.terms); got != c.want {
+				t.Errorf("assembleFilter(%v) = %q, want %q", c.terms, got, c.want)
+			}
+		})
+	}
+}
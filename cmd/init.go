@@ -0,0 +1,146 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/VirusTotal/vt-cli/utils"
+)
+
+const initCmdHelp = `Set up vt-cli interactively.
+
+This walks you through configuring your VT API key and a few handy
+defaults (--threads, --limit, output format), and saves them to your
+configuration file so you don't have to pass them on every invocation.`
+
+// NewInitCmd returns a new instance of the 'init' command.
+func NewInitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Setup vt-cli",
+		Long:  initCmdHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInit()
+		},
+	}
+	return cmd
+}
+
+func runInit() error {
+	if !isInteractive() {
+		return fmt.Errorf("\"vt init\" must be run from an interactive terminal")
+	}
+
+	apikey, err := promptAndValidateAPIKey(viper.GetString("apikey"))
+	if err != nil {
+		return err
+	}
+	viper.Set("apikey", apikey)
+
+	defaults := struct {
+		Threads string
+		Limit   string
+		Format  string
+	}{}
+
+	questions := []*survey.Question{
+		{
+			Name:     "Threads",
+			Prompt:   &survey.Input{Message: "Default number of threads:", Default: "5"},
+			Validate: survey.Required,
+		},
+		{
+			Name:     "Limit",
+			Prompt:   &survey.Input{Message: "Default result limit:", Default: "10"},
+			Validate: survey.Required,
+		},
+		{
+			Name: "Format",
+			Prompt: &survey.Select{
+				Message: "Preferred output format:",
+				Options: []string{"yaml", "json", "ndjson"},
+				Default: "yaml",
+			},
+		},
+	}
+
+	if err := survey.Ask(questions, &defaults); err != nil {
+		return err
+	}
+
+	viper.Set("threads", defaults.Threads)
+	viper.Set("limit", defaults.Limit)
+	viper.Set(defaults.Format, true)
+
+	if err := viper.WriteConfig(); err != nil {
+		return fmt.Errorf("error while saving configuration file: %v", err)
+	}
+
+	fmt.Println("Configuration saved, you are ready to use vt-cli.")
+	return nil
+}
+
+// isInteractive reports whether stdin is attached to a terminal, so prompts
+// can be skipped entirely in scripts and CI.
+func isInteractive() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
+
+// promptAndValidateAPIKey asks the user for their VT API key, using current
+// as the default when one is already configured (leaving the prompt blank
+// keeps it), and keeps prompting until it's able to authenticate with the
+// VT API.
+func promptAndValidateAPIKey(current string) (string, error) {
+	message := "VirusTotal API key:"
+	if current != "" {
+		message = "VirusTotal API key (leave blank to keep the current one):"
+	}
+	for {
+		apikey := ""
+		if err := survey.AskOne(&survey.Password{Message: message}, &apikey); err != nil {
+			return "", err
+		}
+		if apikey == "" {
+			if current == "" {
+				fmt.Println("An API key is required.")
+				continue
+			}
+			apikey = current
+		}
+		if err := validateAPIKey(apikey); err != nil {
+			fmt.Printf("That API key doesn't seem to work: %v\n", err)
+			continue
+		}
+		return apikey, nil
+	}
+}
+
+// validateAPIKey checks that apikey is accepted by the VT API by fetching
+// the authenticated user's own object.
+func validateAPIKey(apikey string) error {
+	client, err := utils.NewAPIClient(apikey, fmt.Sprintf("vt-cli %s", Version))
+	if err != nil {
+		return err
+	}
+	_, err = client.GetObject(&url.URL{Path: "users/" + apikey})
+	return err
+}
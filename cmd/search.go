@@ -0,0 +1,86 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const searchCmdHelp = `Search for files, URLs, domains and IP addresses using VT Intelligence.
+
+The query can be passed directly on the command line, built step by step
+with --interactive, or both (the interactive modifiers are appended to
+whatever was already typed).`
+
+const searchCmdExample = `  vt search "type:peexe size:90kb+ positives:5+"
+  vt search --interactive`
+
+// NewSearchCmd returns a new instance of the 'search' command.
+func NewSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "search [query]",
+		Short:   "Search for files, URLs, domains and IP addresses",
+		Long:    searchCmdHelp,
+		Example: searchCmdExample,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			if len(args) > 0 {
+				query = args[0]
+			}
+			if viper.GetBool("interactive") {
+				if !isInteractive() {
+					return fmt.Errorf("--interactive requires an interactive terminal")
+				}
+				filter, err := BuildFilterInteractively()
+				if err != nil {
+					return err
+				}
+				if query != "" && filter != "" {
+					query = query + " " + filter
+				} else if filter != "" {
+					query = filter
+				}
+			}
+			if query == "" {
+				return fmt.Errorf("a search query is required, pass it directly or use --interactive")
+			}
+			p, err := NewObjectPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			return p.PrintCollection(&url.URL{
+				Path:     "intelligence/search",
+				RawQuery: "query=" + url.QueryEscape(query)})
+		},
+	}
+
+	addThreadsFlag(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+	addIncludeExcludeFlags(cmd.Flags())
+	addLimitFlag(cmd.Flags())
+	addCursorFlag(cmd.Flags())
+	addYAMLFlag(cmd.Flags())
+	addFormatFlag(cmd.Flags())
+	addJSONFlag(cmd.Flags())
+	addNDJSONFlag(cmd.Flags())
+	addInteractiveFlag(cmd.Flags())
+	addProgressFlag(cmd.Flags())
+
+	return cmd
+}
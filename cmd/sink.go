@@ -0,0 +1,186 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/viper"
+
+	"github.com/VirusTotal/vt-cli/formatter"
+	"github.com/VirusTotal/vt-cli/yaml"
+	"github.com/VirusTotal/vt-go/vt"
+)
+
+// ObjectSink receives objects one at a time, in the order they are
+// retrieved, and renders them to an underlying writer. Write may buffer
+// (e.g. to produce a well-formed YAML/JSON document, or to align a table),
+// but implementations that can emit output incrementally, like NDJSON,
+// should do so from Write rather than waiting for Flush, so that
+// long-running --threads/--limit operations can be piped through tools
+// like jq as results arrive.
+type ObjectSink interface {
+	// Write renders a single object.
+	Write(obj *vt.Object) error
+	// Flush writes out any buffered output and flushes the underlying
+	// writer.
+	Flush() error
+}
+
+// newObjectSink returns the ObjectSink that should be used for the current
+// invocation of p's command, based on the --ndjson, --json, --format and
+// --yaml flags, in that order of precedence.
+func (p *ObjectPrinter) newObjectSink() (ObjectSink, error) {
+	switch {
+	case viper.GetBool("ndjson"):
+		return &ndjsonSink{w: p.w}, nil
+	case viper.GetBool("json"):
+		return &jsonSink{w: p.w}, nil
+	}
+	if tmpl := p.formatTemplate(); tmpl != "" {
+		f, err := formatter.New(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		s := &templateSink{w: p.w, f: f}
+		if f.IsTable() {
+			s.tw = tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		}
+		return s, nil
+	}
+	return &yamlSink{w: p.w}, nil
+}
+
+// yamlSink renders objects as a single colorized YAML document, matching
+// the original --yaml (and default) output. The document can't be emitted
+// incrementally since yaml.Encoder renders a list as a whole.
+type yamlSink struct {
+	w    *bufio.Writer
+	objs []map[string]interface{}
+}
+
+func (s *yamlSink) Write(obj *vt.Object) error {
+	s.objs = append(s.objs, objectToYAMLMap(obj))
+	return nil
+}
+
+func (s *yamlSink) Flush() error {
+	if err := yaml.NewColorEncoder(s.w, colorScheme).Encode(s.objs); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// jsonSink renders objects as a single compact JSON array.
+type jsonSink struct {
+	w    *bufio.Writer
+	objs []map[string]interface{}
+}
+
+func (s *jsonSink) Write(obj *vt.Object) error {
+	s.objs = append(s.objs, objectToYAMLMap(obj))
+	return nil
+}
+
+func (s *jsonSink) Flush() error {
+	enc := json.NewEncoder(s.w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s.objs); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// ndjsonSink renders each object as a compact JSON document on its own
+// line, as soon as it is written, so scripts can start consuming results
+// before the whole collection has been retrieved.
+type ndjsonSink struct {
+	w *bufio.Writer
+}
+
+func (s *ndjsonSink) Write(obj *vt.Object) error {
+	enc := json.NewEncoder(s.w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(objectToYAMLMap(obj)); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+func (s *ndjsonSink) Flush() error {
+	return s.w.Flush()
+}
+
+// templateSink renders each object using a --format template. When the
+// template uses the "table " directive, rows are buffered in a
+// text/tabwriter and aligned into columns on Flush.
+type templateSink struct {
+	w  *bufio.Writer
+	f  *formatter.Formatter
+	tw *tabwriter.Writer
+}
+
+func (s *templateSink) Write(obj *vt.Object) error {
+	var buf bytes.Buffer
+	if err := s.f.Execute(&buf, obj); err != nil {
+		return err
+	}
+	dst := s.w
+	if s.tw != nil {
+		if _, err := s.tw.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		fmt.Fprintln(s.tw)
+		return nil
+	}
+	if _, err := dst.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	fmt.Fprintln(dst)
+	return dst.Flush()
+}
+
+func (s *templateSink) Flush() error {
+	if s.tw != nil {
+		if err := s.tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return s.w.Flush()
+}
+
+// objectToYAMLMap turns obj into the map[string]interface{} used by the
+// YAML and JSON sinks, keyed by "<type> <<id>>" as the original --yaml
+// output did, with relationships folded into the attributes as related
+// object identifiers.
+func objectToYAMLMap(obj *vt.Object) map[string]interface{} {
+	m := obj.Attributes
+	for name, r := range obj.Relationships {
+		if r.IsOneToOne && len(r.RelatedObjects) > 0 {
+			m[name] = r.RelatedObjects[0].ID
+		} else {
+			l := make([]string, 0)
+			for _, related := range r.RelatedObjects {
+				l = append(l, related.ID)
+			}
+			m[name] = l
+		}
+	}
+	key := fmt.Sprintf("%s <%s>", obj.Type, obj.ID)
+	return map[string]interface{}{key: m}
+}
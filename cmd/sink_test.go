@@ -0,0 +1,164 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/VirusTotal/vt-cli/formatter"
+	"github.com/VirusTotal/vt-go/vt"
+)
+
+func newSinkBuffer() (*bytes.Buffer, *bufio.Writer) {
+	buf := &bytes.Buffer{}
+	return buf, bufio.NewWriter(buf)
+}
+
+func testFileObject(id string) *vt.Object {
+	return &vt.Object{
+		ID:         id,
+		Type:       "file",
+		Attributes: map[string]interface{}{"type_description": "Win32 EXE"},
+	}
+}
+
+func TestObjectToYAMLMap(t *testing.T) {
+	obj := testFileObject("44d88612fea8a8f36de82e1278abb02f")
+	m := objectToYAMLMap(obj)
+
+	attrs, ok := m["file <44d88612fea8a8f36de82e1278abb02f>"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected key %q, got %v", "file <44d88612fea8a8f36de82e1278abb02f>", m)
+	}
+	if attrs["type_description"] != "Win32 EXE" {
+		t.Errorf("expected type_description attribute to survive, got %v", attrs["type_description"])
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	buf, w := newSinkBuffer()
+	s := &jsonSink{w: w}
+
+	if err := s.Write(testFileObject("aaa")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := s.Write(testFileObject("bbb")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	out := strings.TrimSpace(buf.String())
+	if strings.Count(out, "\n") != 0 {
+		t.Errorf("expected jsonSink to emit a single line (one JSON array), got %q", out)
+	}
+	if !strings.HasPrefix(out, "[") || !strings.HasSuffix(out, "]") {
+		t.Errorf("expected jsonSink output to be a JSON array, got %q", out)
+	}
+	if !strings.Contains(out, "file <aaa>") || !strings.Contains(out, "file <bbb>") {
+		t.Errorf("expected both objects in output, got %q", out)
+	}
+}
+
+func TestNDJSONSinkStreamsPerLine(t *testing.T) {
+	buf, w := newSinkBuffer()
+	s := &ndjsonSink{w: w}
+
+	if err := s.Write(testFileObject("aaa")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	// ndjsonSink must flush as it goes, before Flush/Close, so scripts can
+	// start consuming before the whole collection has been retrieved.
+	firstLine := buf.String()
+	if !strings.Contains(firstLine, "file <aaa>") {
+		t.Fatalf("expected first object to be written immediately, got %q", firstLine)
+	}
+
+	if err := s.Write(testFileObject("bbb")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "file <aaa>") || !strings.Contains(lines[1], "file <bbb>") {
+		t.Errorf("expected one object per line in order, got %q", lines)
+	}
+}
+
+func TestTemplateSink(t *testing.T) {
+	buf, w := newSinkBuffer()
+	f, err := formatter.New("{{.id}}")
+	if err != nil {
+		t.Fatalf("formatter.New returned error: %v", err)
+	}
+	s := &templateSink{w: w, f: f}
+
+	if err := s.Write(testFileObject("aaa")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	if got := strings.TrimSpace(buf.String()); got != "aaa" {
+		t.Errorf("templateSink output = %q, want %q", got, "aaa")
+	}
+}
+
+func TestTemplateSinkTableAlignsColumns(t *testing.T) {
+	buf, w := newSinkBuffer()
+	f, err := formatter.New("table {{.id}}\t{{.attributes.type_description}}")
+	if err != nil {
+		t.Fatalf("formatter.New returned error: %v", err)
+	}
+	s := &templateSink{w: w, f: f}
+	if !f.IsTable() {
+		t.Fatalf("expected formatter to report IsTable() for the table directive")
+	}
+	s.tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	if err := s.Write(testFileObject("aaa")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := s.Write(&vt.Object{
+		ID:         "bbbbbbbb",
+		Type:       "file",
+		Attributes: map[string]interface{}{"type_description": "ELF"},
+	}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 rows, got %d: %q", len(lines), buf.String())
+	}
+	// The second column should start at the same offset in both rows once
+	// tabwriter aligns the columns (the last cell of a row isn't padded).
+	if strings.Index(lines[0], "Win32 EXE") != strings.Index(lines[1], "ELF") {
+		t.Errorf("expected aligned columns, got %q and %q", lines[0], lines[1])
+	}
+}
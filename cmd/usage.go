@@ -0,0 +1,218 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/plusvic/go-ansi"
+
+	"github.com/VirusTotal/vt-cli/formatter"
+	"github.com/VirusTotal/vt-go/vt"
+)
+
+const usageCmdHelp = `Show how your API key is being spent.
+
+Prints your account and group quotas alongside the number of retrohunt
+jobs you've run. In --verbose mode each row also carries the raw
+per-quota detail (API requests, Intelligence searches/downloads, monitor
+storage...) under "detail"; the default table shows only group, used and
+allowed, so the detail appears with --yaml, --json, --ndjson or a --format
+template that uses it. Use
+--since/--until to bound the reported consumption to a time window, and
+--watch to keep the numbers on screen, refreshed at the given interval.`
+
+const usageCmdExample = `  vt usage
+  vt usage --verbose
+  vt usage --watch=30s`
+
+const usageDefaultFormat = "table {{.attributes.group}}\t{{.attributes.used}}\t{{.attributes.allowed}}"
+
+func init() {
+	formatter.RegisterDefault("vt usage", usageDefaultFormat)
+}
+
+// NewUsageCmd returns a new instance of the 'usage' command.
+func NewUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "usage",
+		Aliases: []string{"du"},
+		Short:   "Show quota, storage and collection usage for your API key",
+		Long:    usageCmdHelp,
+		Example: usageCmdExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUsage(cmd)
+		},
+	}
+
+	addYAMLFlag(cmd.Flags())
+	addFormatFlag(cmd.Flags())
+	addJSONFlag(cmd.Flags())
+	addNDJSONFlag(cmd.Flags())
+	addVerboseFlag(cmd.Flags())
+
+	cmd.Flags().String("since", "", "start of the reporting window (YYYY-MM-DD)")
+	cmd.Flags().String("until", "", "end of the reporting window (YYYY-MM-DD)")
+	cmd.Flags().Duration("watch", 0, "re-query and redraw every interval, e.g. 30s (0 disables)")
+
+	return cmd
+}
+
+func runUsage(cmd *cobra.Command) error {
+	p, err := NewObjectPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	watch := viper.GetDuration("watch")
+	if watch <= 0 {
+		return printUsageOnce(p)
+	}
+
+	for {
+		fmt.Fprint(ansi.NewAnsiStdout(), "\033[H\033[2J")
+		if err := printUsageOnce(p); err != nil {
+			return err
+		}
+		time.Sleep(watch)
+	}
+}
+
+// printUsageOnce fetches the two distinct sources "vt usage" reports on
+// (the account's overall_quotas and its retrohunt job count) in parallel
+// and prints the resulting rows through the usual ObjectPrinter pipeline.
+func printUsageOnce(p *ObjectPrinter) error {
+	var (
+		wg       sync.WaitGroup
+		quotas   *vt.Object
+		quotaErr error
+		jobCount int
+		jobErr   error
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		quotas, quotaErr = fetchOverallQuotas(p)
+	}()
+	go func() {
+		defer wg.Done()
+		jobCount, jobErr = countRetrohuntJobs(p)
+	}()
+	wg.Wait()
+
+	if quotaErr != nil {
+		return quotaErr
+	}
+
+	rows := quotaRows(quotas.Attributes, viper.GetBool("verbose"))
+
+	if jobErr != nil {
+		fmt.Fprintf(ansi.NewAnsiStderr(), "retrohunt_jobs: %v\n", jobErr)
+	} else {
+		rows = append(rows, retrohuntRow(jobCount))
+	}
+
+	return p.PrintObjects(rows)
+}
+
+// fetchOverallQuotas retrieves the authenticated user's overall_quotas
+// object, which contains one entry per quota category (api_requests_*,
+// intelligence_*, monitor_*...), optionally bounded by --since/--until.
+func fetchOverallQuotas(p *ObjectPrinter) (*vt.Object, error) {
+	apikey := viper.GetString("apikey")
+	return p.client.GetObject(&url.URL{
+		Path:     fmt.Sprintf("users/%s/overall_quotas", apikey),
+		RawQuery: sinceUntilQuery().Encode()})
+}
+
+// countRetrohuntJobs counts the account's retrohunt jobs using the same
+// vt.Iterator/PrintCollection machinery the rest of this package uses for
+// collections, rather than treating it as a single object.
+func countRetrohuntJobs(p *ObjectPrinter) (int, error) {
+	it, err := p.client.Iterator(
+		&url.URL{Path: "intelligence/retrohunt_jobs", RawQuery: sinceUntilQuery().Encode()},
+		vt.IteratorOptions{})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for it.Next() {
+		count++
+	}
+	return count, it.Error()
+}
+
+func sinceUntilQuery() url.Values {
+	q := url.Values{}
+	if since := viper.GetString("since"); since != "" {
+		q.Set("since", since)
+	}
+	if until := viper.GetString("until"); until != "" {
+		q.Set("until", until)
+	}
+	return q
+}
+
+// quotaRows turns the overall_quotas attributes (one map per quota
+// category, each with at least "allowed" and "used" keys) into one row
+// object per category, sorted by name for stable output. In --verbose
+// mode the category's raw detail is kept under "detail" as well.
+func quotaRows(attrs map[string]interface{}, verbose bool) []*vt.Object {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]*vt.Object, 0, len(names))
+	for _, name := range names {
+		detail, ok := attrs[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		row := map[string]interface{}{
+			"group":   name,
+			"allowed": detail["allowed"],
+			"used":    detail["used"],
+		}
+		if verbose {
+			row["detail"] = detail
+		}
+		rows = append(rows, &vt.Object{ID: name, Type: "quota", Attributes: row})
+	}
+	return rows
+}
+
+// retrohuntRow renders the retrohunt job count as a row with the same
+// group/used/allowed shape as the quota rows, since there's no fixed
+// allowance to report against.
+func retrohuntRow(count int) *vt.Object {
+	return &vt.Object{
+		ID:   "retrohunt_jobs",
+		Type: "quota",
+		Attributes: map[string]interface{}{
+			"group":   "retrohunt_jobs",
+			"used":    count,
+			"allowed": "-",
+		},
+	}
+}
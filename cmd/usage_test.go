@@ -0,0 +1,72 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestQuotaRows(t *testing.T) {
+	attrs := map[string]interface{}{
+		"api_requests_monthly": map[string]interface{}{"allowed": 1000, "used": 42},
+		"intelligence_monthly": map[string]interface{}{"allowed": 100, "used": 7},
+		"not_a_quota":          "this isn't a map and must be skipped",
+	}
+
+	rows := quotaRows(attrs, false)
+
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	// quotaRows sorts by name, so api_requests_monthly comes first.
+	if rows[0].ID != "api_requests_monthly" {
+		t.Errorf("expected first row to be api_requests_monthly, got %s", rows[0].ID)
+	}
+	if rows[0].Attributes["group"] != "api_requests_monthly" {
+		t.Errorf("expected group attribute to be set, got %v", rows[0].Attributes["group"])
+	}
+	if rows[0].Attributes["used"] != 42 {
+		t.Errorf("expected used=42, got %v", rows[0].Attributes["used"])
+	}
+	if rows[0].Attributes["allowed"] != 1000 {
+		t.Errorf("expected allowed=1000, got %v", rows[0].Attributes["allowed"])
+	}
+	if _, ok := rows[0].Attributes["detail"]; ok {
+		t.Errorf("detail should not be present when verbose is false")
+	}
+}
+
+func TestQuotaRowsVerboseKeepsDetail(t *testing.T) {
+	attrs := map[string]interface{}{
+		"api_requests_monthly": map[string]interface{}{"allowed": 1000, "used": 42},
+	}
+
+	rows := quotaRows(attrs, true)
+
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	if _, ok := rows[0].Attributes["detail"]; !ok {
+		t.Errorf("detail should be present when verbose is true")
+	}
+}
+
+func TestRetrohuntRow(t *testing.T) {
+	row := retrohuntRow(3)
+
+	if row.Attributes["group"] != "retrohunt_jobs" {
+		t.Errorf("expected group=retrohunt_jobs, got %v", row.Attributes["group"])
+	}
+	if row.Attributes["used"] != 3 {
+		t.Errorf("expected used=3, got %v", row.Attributes["used"])
+	}
+}
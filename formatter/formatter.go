@@ -0,0 +1,189 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package formatter renders *vt.Object values using Go's text/template
+// syntax. It exists so that commands can offer a --format flag that lets
+// users shape the output (a single field, a one-line summary, a table...)
+// without having to post-process the --yaml output with external tools.
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/VirusTotal/vt-go/vt"
+)
+
+// TablePrefix is the directive used to request aligned table output, as in
+// "table {{.id}}\t{{.attributes.type_description}}".
+const TablePrefix = "table "
+
+// Formatter renders *vt.Object values according to a text/template.
+type Formatter struct {
+	tmpl    *template.Template
+	isTable bool
+}
+
+// New compiles text as a text/template, using FuncMap for the template
+// functions. If text starts with the "table " directive the returned
+// Formatter renders as a table row and IsTable reports true.
+func New(text string) (*Formatter, error) {
+	isTable := false
+	if strings.HasPrefix(text, TablePrefix) {
+		isTable = true
+		text = strings.TrimPrefix(text, TablePrefix)
+	}
+	tmpl, err := template.New("format").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %v", err)
+	}
+	return &Formatter{tmpl: tmpl, isTable: isTable}, nil
+}
+
+// IsTable reports whether the template was written with the "table "
+// directive and therefore wants its output aligned in columns.
+func (f *Formatter) IsTable() bool {
+	return f.isTable
+}
+
+// Execute renders obj and writes the result to buf.
+func (f *Formatter) Execute(buf *bytes.Buffer, obj *vt.Object) error {
+	return f.tmpl.Execute(buf, objectToMap(obj))
+}
+
+// objectToMap turns obj into the data passed to the template, exposing id,
+// type, attributes, relationships and context_attributes as a nested map so
+// that templates can use either dot or index syntax, e.g.
+// {{.attributes.type_description}} or {{index .attributes "type_description"}}.
+func objectToMap(obj *vt.Object) map[string]interface{} {
+	relationships := make(map[string]interface{}, len(obj.Relationships))
+	for name, r := range obj.Relationships {
+		if r.IsOneToOne && len(r.RelatedObjects) > 0 {
+			relationships[name] = r.RelatedObjects[0].ID
+		} else {
+			ids := make([]string, 0, len(r.RelatedObjects))
+			for _, related := range r.RelatedObjects {
+				ids = append(ids, related.ID)
+			}
+			relationships[name] = ids
+		}
+	}
+	return map[string]interface{}{
+		"id":                 obj.ID,
+		"type":               obj.Type,
+		"attributes":         obj.Attributes,
+		"relationships":      relationships,
+		"context_attributes": obj.ContextAttributes,
+	}
+}
+
+// FuncMap returns the template functions available to --format templates.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"join":       join,
+		"truncate":   truncate,
+		"humanBytes": humanBytes,
+		"time":       formatTime,
+		"json":       toJSON,
+	}
+}
+
+// join concatenates the elements of v, converting each one to a string,
+// using sep as the separator. It accepts []string as well as []interface{}
+// so it works directly on relationship and attribute values.
+func join(sep string, v interface{}) string {
+	switch s := v.(type) {
+	case []string:
+		return strings.Join(s, sep)
+	case []interface{}:
+		parts := make([]string, len(s))
+		for i, e := range s {
+			parts[i] = fmt.Sprintf("%v", e)
+		}
+		return strings.Join(parts, sep)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// truncate shortens s to at most n bytes, ending it with "..." when it does
+// (for n of 3 or less, s is just cut to n bytes).
+func truncate(n int, v interface{}) string {
+	s := fmt.Sprintf("%v", v)
+	if len(s) <= n {
+		return s
+	}
+	if n <= 3 {
+		return s[:n]
+	}
+	return s[:n-3] + "..."
+}
+
+// humanBytes formats v, a number of bytes, using binary units (KiB, MiB...).
+func humanBytes(v interface{}) string {
+	size, err := toFloat64(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := 0
+	for size >= 1024 && i < len(units)-1 {
+		size /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", size, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", size, units[i])
+}
+
+// formatTime interprets v as a Unix timestamp, as VT attributes typically
+// are, and renders it using layout (a reference time layout as accepted by
+// time.Format, e.g. "2006-01-02").
+func formatTime(layout string, v interface{}) string {
+	ts, err := toFloat64(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return time.Unix(int64(ts), 0).UTC().Format(layout)
+}
+
+// toJSON marshals v as a compact JSON document. It backs the {{json .}}
+// helper used for producing machine-readable output from a template.
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to number", v)
+	}
+}
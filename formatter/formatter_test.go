@@ -0,0 +1,107 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/VirusTotal/vt-go/vt"
+)
+
+func testObject() *vt.Object {
+	return &vt.Object{
+		ID:   "44d88612fea8a8f36de82e1278abb02f",
+		Type: "file",
+		Attributes: map[string]interface{}{
+			"type_description":   "Win32 EXE",
+			"size":               float64(1048576),
+			"last_analysis_date": float64(1609459200),
+			"tags":               []interface{}{"peexe", "upx"},
+		},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	cases := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"id", "{{.id}}", "44d88612fea8a8f36de82e1278abb02f"},
+		{"type", "{{.type}}", "file"},
+		{"attribute", "{{.attributes.type_description}}", "Win32 EXE"},
+		{"join", `{{join "," .attributes.tags}}`, "peexe,upx"},
+		{"truncate", "{{truncate 4 .attributes.type_description}}", "W..."},
+		{"humanBytes", "{{humanBytes .attributes.size}}", "1.0 MiB"},
+		{"time", `{{time "2006-01-02" .attributes.last_analysis_date}}`, "2021-01-01"},
+		{"json", "{{json .attributes.tags}}", `["peexe","upx"]`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := New(c.tmpl)
+			if err != nil {
+				t.Fatalf("New(%q) returned error: %v", c.tmpl, err)
+			}
+			var buf bytes.Buffer
+			if err := f.Execute(&buf, testObject()); err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if got := buf.String(); got != c.want {
+				t.Errorf("Execute(%q) = %q, want %q", c.tmpl, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsTable(t *testing.T) {
+	f, err := New("table {{.id}}\t{{.attributes.type_description}}")
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if !f.IsTable() {
+		t.Errorf("expected IsTable() to be true for a template using the table directive")
+	}
+
+	var buf bytes.Buffer
+	if err := f.Execute(&buf, testObject()); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if want := "44d88612fea8a8f36de82e1278abb02f\tWin32 EXE"; buf.String() != want {
+		t.Errorf("Execute() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestIsTableFalseWithoutDirective(t *testing.T) {
+	f, err := New("{{.id}}")
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if f.IsTable() {
+		t.Errorf("expected IsTable() to be false without the table directive")
+	}
+}
+
+func TestNewInvalidTemplate(t *testing.T) {
+	if _, err := New("{{.id"); err == nil {
+		t.Errorf("expected New to return an error for an unparseable template")
+	}
+}
+
+func TestHumanBytesNonNumeric(t *testing.T) {
+	if got := humanBytes("not a number"); got != "not a number" {
+		t.Errorf("humanBytes(%q) = %q, want the value echoed back unchanged", "not a number", got)
+	}
+}
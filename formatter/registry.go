@@ -0,0 +1,34 @@
+// Copyright © 2017 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formatter
+
+// defaults holds the per-command default --format template, keyed by the
+// command's full path (e.g. "vt usage"). Commands register their default
+// in an init() function so --yaml remains the fallback when none is set.
+// As of this package only "vt usage" registers one; other commands can
+// call RegisterDefault the same way as they adopt --format.
+var defaults = make(map[string]string)
+
+// RegisterDefault associates cmdPath, a command's full path as returned by
+// cobra.Command.CommandPath, with the template used when the user doesn't
+// pass an explicit --format.
+func RegisterDefault(cmdPath, tmpl string) {
+	defaults[cmdPath] = tmpl
+}
+
+// Default returns the default template registered for cmdPath, if any.
+func Default(cmdPath string) (string, bool) {
+	tmpl, ok := defaults[cmdPath]
+	return tmpl, ok
+}